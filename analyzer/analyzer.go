@@ -0,0 +1,108 @@
+// Package analyzer implements the thymus layering check: it walks a
+// package's imports and reports any edge that the project's declared layer
+// DAG (see package config) does not allow.
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+
+	"github.com/dhaliwalg/thymus/config"
+	"github.com/dhaliwalg/thymus/handlerdetect"
+)
+
+// Analyzer is the thymus architectural-layering check. See cmd/thymus for
+// the prebuilt binary.
+var Analyzer = &analysis.Analyzer{
+	Name:     "thymus",
+	Doc:      "reports import edges that violate the project's declared layer DAG",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+}
+
+// layers is the manifest used by the current run. It defaults to the
+// built-in handler/service/repository chain so Analyzer still does
+// something useful when embedded without a call to SetLayers.
+var layers = config.DefaultLayers()
+
+// SetLayers installs the layer DAG the analyzer checks against, typically
+// the result of config.Load. It must be called before Run executes, e.g.
+// from a cmd's flag-parsing step.
+func SetLayers(l *config.Layers) {
+	if l != nil {
+		layers = l
+	}
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	fromLayer, ok := layers.LayerFor(pass.Pkg.Path())
+	if !ok {
+		fromLayer, ok = detectHandlerLayer(pass)
+	}
+	if !ok {
+		// Package isn't covered by any declared layer; nothing to check.
+		return nil, nil
+	}
+
+	forbidden := layers.ForbiddenFor(fromLayer)
+
+	for _, file := range pass.Files {
+		for _, imp := range file.Imports {
+			path := strings.Trim(imp.Path.Value, `"`)
+
+			for _, f := range forbidden {
+				if path == f || strings.HasPrefix(path, f+"/") {
+					pass.Reportf(imp.Pos(), "layer %q must not import %q", fromLayer, path)
+				}
+			}
+
+			toLayer, ok := layers.LayerFor(path)
+			if !ok {
+				continue
+			}
+			if !layers.IsAllowed(fromLayer, toLayer) {
+				pass.Reportf(imp.Pos(), "layer %q must not import layer %q (%s)", fromLayer, toLayer, path)
+			}
+		}
+	}
+
+	checkInterfaceBoundary(pass, fromLayer)
+	maybeScaffold(pass, fromLayer)
+
+	return nil, nil
+}
+
+// detectHandlerLayer reports whether pass's package contains a function or
+// method whose signature matches a registered handler kind (see
+// handlerdetect), for any layer that opted into DetectHandlers. This lets
+// framework handlers (gin, echo, chi, ...) count as the handler layer even
+// when they don't live in a package the manifest's glob matches.
+func detectHandlerLayer(pass *analysis.Pass) (string, bool) {
+	detectLayers := layers.HandlerDetectLayers()
+	if len(detectLayers) == 0 {
+		return "", false
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			obj := pass.TypesInfo.Defs[fn.Name]
+			if obj == nil {
+				continue
+			}
+			sig, ok := obj.Type().(*types.Signature)
+			if !ok || !handlerdetect.IsHandler(sig) {
+				continue
+			}
+			return detectLayers[0], true
+		}
+	}
+	return "", false
+}
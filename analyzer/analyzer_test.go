@@ -0,0 +1,25 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/dhaliwalg/thymus/analyzer"
+)
+
+// TestGinHandlerDetection checks that a gin.HandlerFunc-shaped method is
+// classified as the handler layer by signature (see handlerdetect) even
+// though its package isn't named "handler", and that the forbidden-import
+// check fires for it the same way it does for BadHandler.
+func TestGinHandlerDetection(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "ginhandler")
+}
+
+// TestInterfaceBoundary checks that a service holding a concrete repository
+// type is flagged, and that the same shape one layer up (a handler holding
+// a concrete service) is not, since the default manifest only sets
+// EnforceInterfaces on the service layer.
+func TestInterfaceBoundary(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "example/...", "examplehandler/...")
+}
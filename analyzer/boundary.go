@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkInterfaceBoundary reports struct fields and function parameters in
+// a layer that opted into EnforceInterfaces (see config.Layer) that reach
+// into a layer it's allowed to import from via a concrete type instead of
+// an interface, e.g. a service holding a concrete *repository.UserRepo
+// instead of a repository.UserRepository. Crossing that boundary through
+// an interface keeps the dependency inverted the way the repository
+// pattern intends; a concrete type pins the caller to one implementation.
+func checkInterfaceBoundary(pass *analysis.Pass, fromLayer string) {
+	if !layers.EnforcesInterfaces(fromLayer) {
+		return
+	}
+
+	check := func(expr ast.Expr, pos ast.Node) {
+		t := pass.TypesInfo.TypeOf(expr)
+		if t == nil {
+			return
+		}
+		named, isPtr := asNamed(t)
+		if named == nil || named.Obj().Pkg() == nil {
+			return
+		}
+
+		toLayer, ok := layers.LayerFor(named.Obj().Pkg().Path())
+		if !ok || toLayer == fromLayer || !layers.IsAllowed(fromLayer, toLayer) {
+			return
+		}
+		if types.IsInterface(named.Obj().Type()) {
+			return
+		}
+
+		msg := "layer %q must depend on layer %q through an interface, not concrete type %s"
+		diag := analysis.Diagnostic{
+			Pos:     pos.Pos(),
+			Message: fmt.Sprintf(msg, fromLayer, toLayer, named.Obj().Name()),
+		}
+		if iface, ok := findImplementedInterface(named, isPtr); ok {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("use interface %s instead", iface.Name()),
+				TextEdits: []analysis.TextEdit{{
+					Pos:     expr.Pos(),
+					End:     expr.End(),
+					NewText: []byte(named.Obj().Pkg().Name() + "." + iface.Name()),
+				}},
+			}}
+		}
+		pass.Report(diag)
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok || st.Fields == nil {
+						continue
+					}
+					for _, field := range st.Fields.List {
+						check(field.Type, field)
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Type.Params == nil {
+					continue
+				}
+				for _, param := range d.Type.Params.List {
+					check(param.Type, param)
+				}
+			}
+		}
+	}
+}
+
+// asNamed unwraps a single pointer indirection and returns the underlying
+// named type, along with whether t was a pointer.
+func asNamed(t types.Type) (*types.Named, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		named, _ := ptr.Elem().(*types.Named)
+		return named, true
+	}
+	named, _ := t.(*types.Named)
+	return named, false
+}
+
+// findImplementedInterface looks in concrete's package for an exported
+// interface that concrete (or *concrete) satisfies, for use as a
+// suggested-fix replacement type.
+func findImplementedInterface(concrete *types.Named, isPtr bool) (*types.TypeName, bool) {
+	pkg := concrete.Obj().Pkg()
+	if pkg == nil {
+		return nil, false
+	}
+
+	var candidate types.Type = concrete
+	if isPtr {
+		candidate = types.NewPointer(concrete)
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || !types.IsInterface(obj.Type()) {
+			continue
+		}
+		iface := obj.Type().Underlying().(*types.Interface)
+		if iface.NumMethods() == 0 {
+			continue
+		}
+		if types.Implements(candidate, iface) {
+			return obj, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/dhaliwalg/thymus/fix"
+)
+
+// fixMode mirrors the -fix flag passed to cmd/thymus: when true, a
+// BadHandler-style violation (a handler holding *sql.DB) is scaffolded into
+// a service/repository pair instead of only being reported.
+var fixMode bool
+
+// SetFixMode enables scaffolding suggested fixes for forbidden-import
+// violations, writing the generated service and repository packages and
+// rewriting the offending handler.
+func SetFixMode(enabled bool) {
+	fixMode = enabled
+}
+
+// maybeScaffold looks for a handler struct in fromLayer's package that
+// holds a *sql.DB field, and, if fixMode is enabled, scaffolds it into a
+// service/repository pair via package fix.
+func maybeScaffold(pass *analysis.Pass, fromLayer string) {
+	if !fixMode || fromLayer != "handler" {
+		return
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		ts := n.(*ast.TypeSpec)
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok || st.Fields == nil {
+			return
+		}
+		fieldName, ok := sqlDBField(st)
+		if !ok {
+			return
+		}
+
+		v := fix.Violation{
+			File:          pass.Fset.Position(ts.Pos()).Filename,
+			HandlerStruct: ts.Name.Name,
+			FieldName:     fieldName,
+			ModulePrefix:  strings.TrimSuffix(pass.Pkg.Path(), "/handler"),
+		}
+		if err := fix.Scaffold(v); err != nil {
+			pass.Reportf(ts.Pos(), "thymus -fix: scaffolding %s: %v", ts.Name.Name, err)
+			return
+		}
+		pass.Reportf(ts.Pos(), "thymus -fix: scaffolded service/repository for %s", ts.Name.Name)
+	})
+}
+
+// sqlDBField returns the name of the first *sql.DB field on st, if any.
+func sqlDBField(st *ast.StructType) (string, bool) {
+	for _, field := range st.Fields.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := star.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "DB" {
+			continue
+		}
+		pkgIdent, ok := sel.X.(*ast.Ident)
+		if !ok || pkgIdent.Name != "sql" {
+			continue
+		}
+		for _, name := range field.Names {
+			return name.Name, true
+		}
+	}
+	return "", false
+}
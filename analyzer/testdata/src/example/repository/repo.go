@@ -0,0 +1,9 @@
+package repository
+
+type UserRepo struct{}
+
+func (r *UserRepo) FindAll() []string { return nil }
+
+type UserRepository interface {
+	FindAll() []string
+}
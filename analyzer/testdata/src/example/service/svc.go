@@ -0,0 +1,7 @@
+package service
+
+import "example/repository"
+
+type UserService struct {
+	repo *repository.UserRepo // want `layer "service" must depend on layer "repository" through an interface, not concrete type UserRepo`
+}
@@ -0,0 +1,10 @@
+// Package handler holds a *service.UserService, a concrete type crossing
+// the handler -> service boundary. checkInterfaceBoundary only applies to
+// the service -> repository boundary, so this must not be flagged.
+package handler
+
+import "examplehandler/service"
+
+type UserHandler struct {
+	svc *service.UserService
+}
@@ -0,0 +1,5 @@
+package service
+
+type UserService struct{}
+
+func (s *UserService) FindAll() []string { return nil }
@@ -0,0 +1,18 @@
+// Package ginhandler is a gin-style handler that doesn't live in a package
+// named "handler", so only signature-based detection (see handlerdetect)
+// flags it as the handler layer.
+package ginhandler
+
+import (
+	"database/sql" // want `layer "handler" must not import "database/sql"`
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	db *sql.DB
+}
+
+func (h *Handler) Show(c *gin.Context) {
+	_ = h.db
+}
@@ -0,0 +1,5 @@
+// Package gin is a stand-in for github.com/gin-gonic/gin, just enough of
+// its surface for testdata/src/ginhandler to exercise handlerdetect.
+package gin
+
+type Context struct{}
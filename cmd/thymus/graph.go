@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dhaliwalg/thymus/config"
+	"github.com/dhaliwalg/thymus/graph"
+)
+
+// runGraph implements `thymus graph`: it builds the whole-module import
+// graph, reports cycles and skip-layer edges, and optionally renders the
+// graph as Graphviz dot or Mermaid for attaching to a PR.
+func runGraph(args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	configPath := fs.String("config", ".thymus.yaml", "path to the layer manifest")
+	dotPath := fs.String("dot", "", "write the import graph as Graphviz dot to this path")
+	mermaidPath := fs.String("mermaid", "", "write the import graph as Mermaid to this path")
+	fs.Parse(args)
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	layers, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	g, err := graph.Build(patterns, layers)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	failed := false
+
+	for _, cycle := range g.SCCs() {
+		failed = true
+		fmt.Printf("import cycle: %v\n", cycle.Packages)
+	}
+	for _, skip := range g.SkipLayerEdges(layers) {
+		failed = true
+		fmt.Printf("%s (%s) skips a layer importing %s (%s) directly\n",
+			skip.From, skip.FromLayer, skip.To, skip.ToLayer)
+	}
+
+	if *dotPath != "" {
+		if err := os.WriteFile(*dotPath, []byte(g.ToDot()), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *mermaidPath != "" {
+		if err := os.WriteFile(*mermaidPath, []byte(g.ToMermaid()), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,89 @@
+// Command thymus enforces a project's declared architectural layering by
+// running the thymus analyzer over its packages.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/dhaliwalg/thymus/analyzer"
+	"github.com/dhaliwalg/thymus/config"
+)
+
+var (
+	configPath = flag.String("config", ".thymus.yaml", "path to the layer manifest")
+	doFix      = flag.Bool("fix", false, "scaffold a service/repository pair for each BadHandler-style violation instead of only reporting it")
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		runGraph(os.Args[2:])
+		return
+	}
+	runLint()
+}
+
+func runLint() {
+	flag.Parse()
+
+	layers, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	analyzer.SetLayers(layers)
+	analyzer.SetFixMode(*doFix)
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports,
+	}, patterns...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, pkg := range pkgs {
+		base := &analysis.Pass{
+			Fset:      pkg.Fset,
+			Files:     pkg.Syntax,
+			Pkg:       pkg.Types,
+			TypesInfo: pkg.TypesInfo,
+		}
+
+		inspectPass := *base
+		inspectPass.Analyzer = inspect.Analyzer
+		inspectResult, err := inspect.Analyzer.Run(&inspectPass)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+
+		pass := *base
+		pass.Analyzer = analyzer.Analyzer
+		pass.ResultOf = map[*analysis.Analyzer]interface{}{inspect.Analyzer: inspectResult}
+		pass.Report = func(d analysis.Diagnostic) {
+			failed = true
+			fmt.Println(pkg.Fset.Position(d.Pos).String() + ": " + d.Message)
+		}
+		if _, err := analyzer.Analyzer.Run(&pass); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,174 @@
+// Package config loads the .thymus.yaml manifest that declares a project's
+// architectural layers and the import edges allowed between them.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Layer describes one architectural layer: a glob matching the packages that
+// belong to it, plus any imports those packages are never allowed to use
+// regardless of what the layer DAG permits (e.g. a "web" layer forbidding
+// "database/sql" even though it may legitimately depend on a lower layer
+// that itself talks to the database).
+type Layer struct {
+	Name      string   `yaml:"name"`
+	Package   string   `yaml:"package"`
+	Forbidden []string `yaml:"forbidden"`
+
+	// DetectHandlers opts this layer into matching packages by function
+	// signature (see handlerdetect) in addition to Package, so framework
+	// handlers (gin, echo, chi, ...) count as this layer even when they
+	// don't live in a package whose name matches the glob.
+	DetectHandlers bool `yaml:"detectHandlers"`
+
+	// EnforceInterfaces requires this layer's struct fields and function
+	// parameters that depend on a layer it's allowed to import from to be
+	// interfaces rather than concrete types, e.g. a service holding a
+	// repository.UserRepository instead of a *repository.UserRepo. This is
+	// the repository-pattern rule: crossing the boundary through an
+	// interface keeps the dependency inverted.
+	EnforceInterfaces bool `yaml:"enforceInterfaces"`
+}
+
+// Layers is the parsed form of a .thymus.yaml manifest: the set of declared
+// layers and the adjacency list describing which layers each layer may
+// import from.
+type Layers struct {
+	Layers []Layer             `yaml:"layers"`
+	Allow  map[string][]string `yaml:"allow"`
+}
+
+// DefaultLayers returns the handler -> service -> repository chain thymus
+// enforced before manifests existed. It is used whenever a project has no
+// .thymus.yaml, so existing setups keep behaving the way they always have.
+func DefaultLayers() *Layers {
+	return &Layers{
+		Layers: []Layer{
+			{Name: "handler", Package: "*/handler", Forbidden: []string{"database/sql"}, DetectHandlers: true},
+			{Name: "service", Package: "*/service", EnforceInterfaces: true},
+			{Name: "repository", Package: "*/repository"},
+		},
+		Allow: map[string][]string{
+			"handler": {"service"},
+			"service": {"repository"},
+		},
+	}
+}
+
+// Load reads and parses the manifest at path. A missing file is not an
+// error: it falls back to DefaultLayers so projects can adopt thymus before
+// they've written a manifest.
+func Load(path string) (*Layers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultLayers(), nil
+		}
+		return nil, fmt.Errorf("config: reading %s: %w", path, err)
+	}
+
+	var l Layers
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+	if err := l.validate(); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// validate checks that every layer named in the allow-list is actually
+// declared, so a typo in the manifest fails loudly instead of silently
+// matching nothing.
+func (l *Layers) validate() error {
+	names := make(map[string]bool, len(l.Layers))
+	for _, layer := range l.Layers {
+		names[layer.Name] = true
+	}
+	for from, tos := range l.Allow {
+		if !names[from] {
+			return fmt.Errorf("allow list references undeclared layer %q", from)
+		}
+		for _, to := range tos {
+			if !names[to] {
+				return fmt.Errorf("allow list for %q references undeclared layer %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// LayerFor returns the name of the layer whose package glob matches pkgPath,
+// and whether any layer matched at all.
+func (l *Layers) LayerFor(pkgPath string) (string, bool) {
+	for _, layer := range l.Layers {
+		if ok, _ := path.Match(layer.Package, pkgPath); ok {
+			return layer.Name, true
+		}
+		// Package globs are written relative to the project, e.g. "*/handler"
+		// matching ".../src/handler"; also try matching against the final
+		// two path elements so manifests don't have to know the full prefix.
+		if ok, _ := path.Match(layer.Package, lastTwo(pkgPath)); ok {
+			return layer.Name, true
+		}
+	}
+	return "", false
+}
+
+// ForbiddenFor returns the forbidden-import list declared for layer name.
+func (l *Layers) ForbiddenFor(name string) []string {
+	for _, layer := range l.Layers {
+		if layer.Name == name {
+			return layer.Forbidden
+		}
+	}
+	return nil
+}
+
+// IsAllowed reports whether a package in layer "from" may import a package
+// in layer "to". Importing within the same layer is always allowed.
+func (l *Layers) IsAllowed(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range l.Allow[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// EnforcesInterfaces reports whether the named layer requires its
+// dependencies on layers it's allowed to import from to be interfaces.
+func (l *Layers) EnforcesInterfaces(name string) bool {
+	for _, layer := range l.Layers {
+		if layer.Name == name {
+			return layer.EnforceInterfaces
+		}
+	}
+	return false
+}
+
+// HandlerDetectLayers returns the names of layers that opted into
+// signature-based handler detection.
+func (l *Layers) HandlerDetectLayers() []string {
+	var names []string
+	for _, layer := range l.Layers {
+		if layer.DetectHandlers {
+			names = append(names, layer.Name)
+		}
+	}
+	return names
+}
+
+func lastTwo(pkgPath string) string {
+	dir, base := path.Split(pkgPath)
+	dir = path.Clean(dir)
+	return path.Join(path.Base(dir), base)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadMissingFileFallsBackToDefault(t *testing.T) {
+	l, err := Load("testdata/does-not-exist.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if toLayer, ok := l.LayerFor("app/service"); !ok || toLayer != "service" {
+		t.Errorf("LayerFor(app/service) = %q, %v, want service, true", toLayer, ok)
+	}
+}
+
+func TestLoadParsesCustomLayerChain(t *testing.T) {
+	l, err := Load("testdata/hexagonal.thymus.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(l.Layers) != 4 {
+		t.Fatalf("len(Layers) = %d, want 4", len(l.Layers))
+	}
+	if !l.IsAllowed("web", "usecase") {
+		t.Error(`IsAllowed("web", "usecase") = false, want true`)
+	}
+	if !l.IsAllowed("usecase", "gateway") {
+		t.Error(`IsAllowed("usecase", "gateway") = false, want true`)
+	}
+	if l.IsAllowed("web", "gateway") {
+		t.Error(`IsAllowed("web", "gateway") = true, want false (not adjacent in the DAG)`)
+	}
+	if !l.EnforcesInterfaces("usecase") {
+		t.Error(`EnforcesInterfaces("usecase") = false, want true`)
+	}
+	if l.EnforcesInterfaces("gateway") {
+		t.Error(`EnforcesInterfaces("gateway") = true, want false`)
+	}
+	if got := l.ForbiddenFor("infra"); len(got) != 1 || got[0] != "net/http" {
+		t.Errorf("ForbiddenFor(infra) = %v, want [net/http]", got)
+	}
+}
+
+func TestLoadRejectsUndeclaredLayerInAllowList(t *testing.T) {
+	_, err := Load("testdata/undeclared_layer.thymus.yaml")
+	if err == nil {
+		t.Fatal("Load: want error for allow list referencing undeclared layer, got nil")
+	}
+	if !strings.Contains(err.Error(), "undeclared layer") {
+		t.Errorf("Load error = %q, want it to mention the undeclared layer", err)
+	}
+}
+
+func TestLayerForTwoSegmentGlobFallback(t *testing.T) {
+	l := DefaultLayers()
+	toLayer, ok := l.LayerFor("github.com/example/myapp/src/service")
+	if !ok || toLayer != "service" {
+		t.Errorf("LayerFor(.../src/service) = %q, %v, want service, true", toLayer, ok)
+	}
+	if _, ok := l.LayerFor("github.com/example/myapp/src/service/internal"); ok {
+		t.Error("LayerFor(.../src/service/internal) matched, want no match (three segments deep)")
+	}
+}
+
+func TestIsAllowedSameLayer(t *testing.T) {
+	l := DefaultLayers()
+	if !l.IsAllowed("service", "service") {
+		t.Error(`IsAllowed("service", "service") = false, want true`)
+	}
+}
+
+func TestHandlerDetectLayers(t *testing.T) {
+	l := DefaultLayers()
+	got := l.HandlerDetectLayers()
+	if len(got) != 1 || got[0] != "handler" {
+		t.Errorf("HandlerDetectLayers() = %v, want [handler]", got)
+	}
+}
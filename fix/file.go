@@ -0,0 +1,27 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+)
+
+func parseFile(fset *token.FileSet, path string) (*ast.File, error) {
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("fix: parsing %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func writeFormattedFile(path string, fset *token.FileSet, file *ast.File) error {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return fmt.Errorf("fix: formatting %s: %w", path, err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
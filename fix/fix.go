@@ -0,0 +1,310 @@
+// Package fix implements thymus's -fix scaffolding. Given a BadHandler-style
+// violation (a handler holding a *sql.DB and querying it directly), it
+// generates a service package and a repository package for the extracted
+// call and rewrites the handler to depend on the new service through
+// constructor injection.
+package fix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Violation describes one handler that reaches straight into the database,
+// as found by the analyzer's forbidden-import check.
+type Violation struct {
+	File          string // path to the handler's source file
+	HandlerStruct string // e.g. "BadHandler"
+	FieldName     string // the *sql.DB field, e.g. "db"
+	ModulePrefix  string // import path corresponding to filepath.Dir(filepath.Dir(File)), e.g. "github.com/example/myapp/src"
+}
+
+// entityName derives the domain name the generated service/repository are
+// named after, e.g. "BadHandler" -> "Bad". Handlers that don't follow the
+// Foo+Handler convention fall back to "Entity".
+func (v Violation) entityName() string {
+	name := strings.TrimSuffix(v.HandlerStruct, "Handler")
+	if name == "" || name == v.HandlerStruct {
+		return "Entity"
+	}
+	return name
+}
+
+// sqlMethods are the *sql.DB methods a handler might call directly; any of
+// these being extracted carries the query text through to the generated
+// repository.
+var sqlMethods = map[string]bool{"Query": true, "QueryRow": true, "Exec": true}
+
+// Scaffold writes the repository and service packages sibling to the
+// handler's package and rewrites the handler to use the new service.
+func Scaffold(v Violation) error {
+	entity := v.entityName()
+	root := filepath.Dir(filepath.Dir(v.File))
+
+	query, err := rewriteHandler(v, entity)
+	if err != nil {
+		return err
+	}
+
+	if err := writeRepository(root, entity, query); err != nil {
+		return err
+	}
+	return writeService(root, entity, v.ModulePrefix)
+}
+
+func writeRepository(root, entity, query string) error {
+	src := fmt.Sprintf(`package repository
+
+import "database/sql"
+
+type %[1]sRepo struct {
+	db *sql.DB
+}
+
+func New%[1]sRepo(db *sql.DB) *%[1]sRepo {
+	return &%[1]sRepo{db: db}
+}
+
+type %[1]sRepository interface {
+	FindAll() []string
+}
+
+func (r *%[1]sRepo) FindAll() []string {
+	rows, _ := r.db.Query(%[2]q)
+	_ = rows
+	return nil
+}
+`, entity, query)
+	return writeGoFile(filepath.Join(root, "repository", entity+"Repo.go"), src)
+}
+
+func writeService(root, entity, modulePrefix string) error {
+	src := fmt.Sprintf(`package service
+
+import "%[2]s/repository"
+
+type %[1]sService struct {
+	repo repository.%[1]sRepository
+}
+
+func New%[1]sService(repo repository.%[1]sRepository) *%[1]sService {
+	return &%[1]sService{repo: repo}
+}
+
+func (s *%[1]sService) FindAll() []string {
+	return s.repo.FindAll()
+}
+`, entity, modulePrefix)
+	return writeGoFile(filepath.Join(root, "service", entity+"Service.go"), src)
+}
+
+func writeGoFile(path, src string) error {
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("fix: formatting generated %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("fix: creating %s: %w", filepath.Dir(path), err)
+	}
+	return os.WriteFile(path, formatted, 0o644)
+}
+
+// rewriteHandler replaces the handler's *sql.DB field with a service field,
+// rewrites every call site that queried the field directly to instead call
+// the new service, and swaps the database/sql import for the service
+// import. It returns the query text extracted from the original call site,
+// for writeRepository to reuse rather than invent.
+func rewriteHandler(v Violation, entity string) (query string, err error) {
+	fset := token.NewFileSet()
+	file, err := parseFile(fset, v.File)
+	if err != nil {
+		return "", err
+	}
+
+	svcField := strings.ToLower(entity[:1]) + "svc"
+
+	if !renameField(file, v.FieldName, entity) {
+		return "", fmt.Errorf("fix: field %q not found on %s", v.FieldName, v.HandlerStruct)
+	}
+
+	query, ok := rewriteCallSites(file, v.FieldName, svcField)
+	if !ok {
+		return "", fmt.Errorf("fix: no call to %s.%s(...) found on %s", v.FieldName, sqlMethodNames(), v.HandlerStruct)
+	}
+
+	replaceImport(file, "database/sql", v.ModulePrefix+"/service")
+
+	if err := writeFormattedFile(v.File, fset, file); err != nil {
+		return "", err
+	}
+	return query, nil
+}
+
+// renameField renames the struct field named fieldName to a service field
+// of type *service.<entity>Service, reporting whether it found one.
+func renameField(file *ast.File, fieldName, entity string) bool {
+	svcField := strings.ToLower(entity[:1]) + "svc"
+	changed := false
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok || st.Fields == nil {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			for _, name := range field.Names {
+				if name.Name != fieldName {
+					continue
+				}
+				name.Name = svcField
+				field.Type = &ast.StarExpr{X: &ast.SelectorExpr{
+					X:   ast.NewIdent("service"),
+					Sel: ast.NewIdent(entity + "Service"),
+				}}
+				changed = true
+			}
+		}
+		return true
+	})
+	return changed
+}
+
+// rewriteCallSites finds every assignment that calls a *sql.DB method on
+// fieldName (e.g. `rows, _ := h.db.Query("...")`) and replaces it, and any
+// later statement in the same block that only existed to discard one of
+// its results (e.g. a trailing `_ = rows`), with a single call to the new
+// service field (`_ = h.<svcField>.FindAll()`). It returns the query text
+// of the first call site it rewrote.
+func rewriteCallSites(file *ast.File, fieldName, svcField string) (query string, found bool) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+
+		for i := 0; i < len(block.List); i++ {
+			assign, ok := block.List[i].(*ast.AssignStmt)
+			if !ok {
+				continue
+			}
+			recv, q, ok := matchSQLCall(assign, fieldName)
+			if !ok {
+				continue
+			}
+			if !found {
+				query = q
+				found = true
+			}
+
+			discarded := discardedNames(assign)
+			replacement := &ast.AssignStmt{
+				Lhs: []ast.Expr{ast.NewIdent("_")},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{&ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   &ast.SelectorExpr{X: ast.NewIdent(recv), Sel: ast.NewIdent(svcField)},
+						Sel: ast.NewIdent("FindAll"),
+					},
+				}},
+			}
+
+			rest := block.List[i+1:]
+			block.List = append(block.List[:i:i], replacement)
+			for _, later := range rest {
+				if !isBlankDiscardOf(later, discarded) {
+					block.List = append(block.List, later)
+				}
+			}
+		}
+		return true
+	})
+	return query, found
+}
+
+// discardedNames returns the names assign declares via `:=` that aren't
+// already blank, e.g. {"rows"} for `rows, _ := h.db.Query(...)`.
+func discardedNames(assign *ast.AssignStmt) map[string]bool {
+	names := map[string]bool{}
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name != "_" {
+			names[ident.Name] = true
+		}
+	}
+	return names
+}
+
+// isBlankDiscardOf reports whether stmt is exactly `_ = name` for one of
+// names, the idiom used to silence "declared and not used" on a variable
+// that's otherwise unread.
+func isBlankDiscardOf(stmt ast.Stmt, names map[string]bool) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	blank, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || blank.Name != "_" {
+		return false
+	}
+	ident, ok := assign.Rhs[0].(*ast.Ident)
+	return ok && names[ident.Name]
+}
+
+// matchSQLCall reports whether assign's right-hand side is a call of the
+// form `<recv>.<fieldName>.Query(...)` (or QueryRow/Exec), returning the
+// receiver identifier and the literal query text passed to it.
+func matchSQLCall(assign *ast.AssignStmt, fieldName string) (recv, query string, ok bool) {
+	if len(assign.Rhs) != 1 {
+		return "", "", false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return "", "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !sqlMethods[sel.Sel.Name] {
+		return "", "", false
+	}
+	fieldSel, ok := sel.X.(*ast.SelectorExpr)
+	if !ok || fieldSel.Sel.Name != fieldName {
+		return "", "", false
+	}
+	recvIdent, ok := fieldSel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	if len(call.Args) == 0 {
+		return "", "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", false
+	}
+	text, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", false
+	}
+	return recvIdent.Name, text, true
+}
+
+func sqlMethodNames() string {
+	names := make([]string, 0, len(sqlMethods))
+	for name := range sqlMethods {
+		names = append(names, name)
+	}
+	return strings.Join(names, "/")
+}
+
+func replaceImport(file *ast.File, oldPath, newPath string) {
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) == oldPath {
+			imp.Path.Value = `"` + newPath + `"`
+			return
+		}
+	}
+}
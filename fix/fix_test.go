@@ -0,0 +1,75 @@
+package fix_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhaliwalg/thymus/fix"
+)
+
+// TestScaffoldBadHandler runs Scaffold against a copy of the BadHandler
+// fixture and checks the rewritten handler and generated service and
+// repository packages byte-for-byte against golden files, so a regression
+// in call-site rewriting or query-text extraction fails loudly instead of
+// only showing up as a `go build` error downstream.
+func TestScaffoldBadHandler(t *testing.T) {
+	srcRoot := filepath.Join("testdata", "badhandler", "src")
+	goldenRoot := filepath.Join("testdata", "badhandler", "golden", "src")
+
+	tmp := t.TempDir()
+	copyTree(t, srcRoot, tmp)
+
+	v := fix.Violation{
+		File:          filepath.Join(tmp, "handler", "bad_handler.go"),
+		HandlerStruct: "BadHandler",
+		FieldName:     "db",
+		ModulePrefix:  "github.com/example/myapp/src",
+	}
+	if err := fix.Scaffold(v); err != nil {
+		t.Fatalf("Scaffold: %v", err)
+	}
+
+	for _, rel := range []string{
+		filepath.Join("handler", "bad_handler.go"),
+		filepath.Join("repository", "BadRepo.go"),
+		filepath.Join("service", "BadService.go"),
+	} {
+		got, err := os.ReadFile(filepath.Join(tmp, rel))
+		if err != nil {
+			t.Fatalf("reading generated %s: %v", rel, err)
+		}
+		want, err := os.ReadFile(filepath.Join(goldenRoot, rel))
+		if err != nil {
+			t.Fatalf("reading golden %s: %v", rel, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", rel, got, want)
+		}
+	}
+}
+
+func copyTree(t *testing.T, src, dst string) {
+	t.Helper()
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+	if err != nil {
+		t.Fatalf("copying fixture tree: %v", err)
+	}
+}
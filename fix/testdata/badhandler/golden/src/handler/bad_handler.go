@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/example/myapp/src/service"
+	"net/http"
+)
+
+// BadHandler directly accesses the database — violation
+type BadHandler struct {
+	bsvc *service.BadService
+}
+
+func (h *BadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = h.bsvc.FindAll()
+
+}
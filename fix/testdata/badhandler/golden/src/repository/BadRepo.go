@@ -0,0 +1,21 @@
+package repository
+
+import "database/sql"
+
+type BadRepo struct {
+	db *sql.DB
+}
+
+func NewBadRepo(db *sql.DB) *BadRepo {
+	return &BadRepo{db: db}
+}
+
+type BadRepository interface {
+	FindAll() []string
+}
+
+func (r *BadRepo) FindAll() []string {
+	rows, _ := r.db.Query("SELECT * FROM users")
+	_ = rows
+	return nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import "github.com/example/myapp/src/repository"
+
+type BadService struct {
+	repo repository.BadRepository
+}
+
+func NewBadService(repo repository.BadRepository) *BadService {
+	return &BadService{repo: repo}
+}
+
+func (s *BadService) FindAll() []string {
+	return s.repo.FindAll()
+}
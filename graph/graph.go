@@ -0,0 +1,214 @@
+// Package graph builds the whole-module inter-package import graph and
+// reports two violation classes the per-package thymus analyzer can't see
+// on its own: import cycles spanning more than one package, and edges that
+// skip over a declared layer (e.g. handler -> repository, bypassing
+// service). It loads only package names, imports and module info (no
+// types or syntax), so it scales to a module of hundreds of packages
+// without paying for type-checking.
+package graph
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/dhaliwalg/thymus/config"
+)
+
+// Node is one package in the import graph, colored by the layer (if any)
+// its path matches in the manifest.
+type Node struct {
+	Path  string
+	Layer string
+}
+
+// Graph is the module's import graph: every loaded package plus the edges
+// to the packages it imports.
+type Graph struct {
+	Nodes map[string]*Node
+	Edges map[string][]string // from package path -> imported package paths
+}
+
+// Build loads every package matching patterns and constructs the import
+// graph, coloring each node by the layer its path matches in layers.
+func Build(patterns []string, layers *config.Layers) (*Graph, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+	}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("graph: loading packages: %w", err)
+	}
+
+	g := &Graph{Nodes: map[string]*Node{}, Edges: map[string][]string{}}
+
+	var visit func(pkg *packages.Package)
+	visit = func(pkg *packages.Package) {
+		if !isMainModule(pkg) {
+			return
+		}
+		if _, ok := g.Nodes[pkg.PkgPath]; ok {
+			return
+		}
+		layer, _ := layers.LayerFor(pkg.PkgPath)
+		g.Nodes[pkg.PkgPath] = &Node{Path: pkg.PkgPath, Layer: layer}
+
+		for _, imp := range pkg.Imports {
+			if !isMainModule(imp) {
+				continue
+			}
+			g.Edges[pkg.PkgPath] = append(g.Edges[pkg.PkgPath], imp.PkgPath)
+			visit(imp)
+		}
+	}
+	for _, pkg := range pkgs {
+		visit(pkg)
+	}
+
+	return g, nil
+}
+
+// isMainModule reports whether pkg belongs to the module being graphed, as
+// opposed to the standard library or a third-party dependency. Those don't
+// belong on an architecture diagram and would otherwise dwarf the project's
+// own packages with hundreds of irrelevant stdlib nodes.
+func isMainModule(pkg *packages.Package) bool {
+	return pkg.Module != nil && pkg.Module.Main
+}
+
+// Cycle is one strongly connected component of more than one package,
+// i.e. a genuine import cycle rather than a single self-recursive package.
+type Cycle struct {
+	Packages []string
+}
+
+// SCCs returns every strongly connected component of the import graph with
+// more than one package, using Tarjan's algorithm (O(V+E)).
+func (g *Graph) SCCs() []Cycle {
+	t := &tarjan{
+		graph:   g,
+		index:   map[string]int{},
+		lowlink: map[string]int{},
+		onStack: map[string]bool{},
+	}
+	for path := range g.Nodes {
+		if _, visited := t.index[path]; !visited {
+			t.strongConnect(path)
+		}
+	}
+
+	var cycles []Cycle
+	for _, scc := range t.sccs {
+		if len(scc) > 1 {
+			cycles = append(cycles, Cycle{Packages: scc})
+		}
+	}
+	return cycles
+}
+
+type tarjan struct {
+	graph   *Graph
+	index   map[string]int
+	lowlink map[string]int
+	onStack map[string]bool
+	stack   []string
+	next    int
+	sccs    [][]string
+}
+
+func (t *tarjan) strongConnect(v string) {
+	t.index[v] = t.next
+	t.lowlink[v] = t.next
+	t.next++
+	t.stack = append(t.stack, v)
+	t.onStack[v] = true
+
+	for _, w := range t.graph.Edges[v] {
+		if _, visited := t.index[w]; !visited {
+			t.strongConnect(w)
+			if t.lowlink[w] < t.lowlink[v] {
+				t.lowlink[v] = t.lowlink[w]
+			}
+		} else if t.onStack[w] {
+			if t.index[w] < t.lowlink[v] {
+				t.lowlink[v] = t.index[w]
+			}
+		}
+	}
+
+	if t.lowlink[v] != t.index[v] {
+		return
+	}
+
+	var scc []string
+	for {
+		n := len(t.stack) - 1
+		w := t.stack[n]
+		t.stack = t.stack[:n]
+		t.onStack[w] = false
+		scc = append(scc, w)
+		if w == v {
+			break
+		}
+	}
+	t.sccs = append(t.sccs, scc)
+}
+
+// SkipLayerEdge is an import that bypasses a declared intermediate layer,
+// e.g. handler -> repository when the manifest only allows
+// handler -> service -> repository.
+type SkipLayerEdge struct {
+	From, To           string // package paths
+	FromLayer, ToLayer string
+}
+
+// SkipLayerEdges returns every import edge whose source and destination
+// layers aren't directly connected in the manifest's allow-list but are
+// reachable through at least one intermediate layer, i.e. edges that skip
+// over that intermediate rather than routing through it.
+func (g *Graph) SkipLayerEdges(layers *config.Layers) []SkipLayerEdge {
+	var skips []SkipLayerEdge
+	for from, tos := range g.Edges {
+		fromLayer := g.Nodes[from].Layer
+		if fromLayer == "" {
+			continue
+		}
+		for _, to := range tos {
+			toLayer := g.Nodes[to].Layer
+			if toLayer == "" || toLayer == fromLayer {
+				continue
+			}
+			if layers.IsAllowed(fromLayer, toLayer) {
+				continue
+			}
+			if reachableThroughIntermediate(layers, fromLayer, toLayer) {
+				skips = append(skips, SkipLayerEdge{From: from, To: to, FromLayer: fromLayer, ToLayer: toLayer})
+			}
+		}
+	}
+	return skips
+}
+
+// reachableThroughIntermediate reports whether to is reachable from from in
+// the layer DAG via a path of two or more hops. A direct edge from -> to
+// doesn't affect the result; SkipLayerEdges checks that separately.
+func reachableThroughIntermediate(layers *config.Layers, from, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := append([]string{}, layers.Allow[from]...)
+	for _, n := range queue {
+		visited[n] = true
+	}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, n := range layers.Allow[next] {
+			if n == to {
+				return true
+			}
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return false
+}
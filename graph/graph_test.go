@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dhaliwalg/thymus/config"
+)
+
+func TestSCCsDetectsTwoPackageCycle(t *testing.T) {
+	g := &Graph{
+		Nodes: map[string]*Node{
+			"a": {Path: "a"},
+			"b": {Path: "b"},
+			"c": {Path: "c"},
+		},
+		Edges: map[string][]string{
+			"a": {"b"},
+			"b": {"a"},
+			"c": {"a"},
+		},
+	}
+
+	cycles := g.SCCs()
+	if len(cycles) != 1 {
+		t.Fatalf("SCCs() = %d cycles, want 1: %v", len(cycles), cycles)
+	}
+
+	got := append([]string{}, cycles[0].Packages...)
+	sort.Strings(got)
+	want := []string{"a", "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SCCs()[0].Packages = %v, want %v", got, want)
+	}
+}
+
+func TestSCCsIgnoresSinglePackageSelfEdges(t *testing.T) {
+	g := &Graph{
+		Nodes: map[string]*Node{"a": {Path: "a"}},
+		Edges: map[string][]string{"a": {"a"}},
+	}
+	if cycles := g.SCCs(); len(cycles) != 0 {
+		t.Errorf("SCCs() = %v, want none for a single self-recursive package", cycles)
+	}
+}
+
+func TestSkipLayerEdgesDetectsHandlerToRepository(t *testing.T) {
+	layers := &config.Layers{
+		Layers: []config.Layer{
+			{Name: "handler", Package: "*/handler"},
+			{Name: "service", Package: "*/service"},
+			{Name: "repository", Package: "*/repository"},
+		},
+		Allow: map[string][]string{
+			"handler": {"service"},
+			"service": {"repository"},
+		},
+	}
+
+	g := &Graph{
+		Nodes: map[string]*Node{
+			"app/handler":    {Path: "app/handler", Layer: "handler"},
+			"app/service":    {Path: "app/service", Layer: "service"},
+			"app/repository": {Path: "app/repository", Layer: "repository"},
+		},
+		Edges: map[string][]string{
+			"app/handler": {"app/service", "app/repository"}, // the second edge skips service
+			"app/service": {"app/repository"},
+		},
+	}
+
+	skips := g.SkipLayerEdges(layers)
+	if len(skips) != 1 {
+		t.Fatalf("SkipLayerEdges() = %d edges, want 1: %v", len(skips), skips)
+	}
+	if skips[0].From != "app/handler" || skips[0].To != "app/repository" {
+		t.Errorf("SkipLayerEdges()[0] = %+v, want From app/handler To app/repository", skips[0])
+	}
+}
+
+// TestBuildHandlerServiceRepositoryFixture drives Build end-to-end against
+// the chunk's three-package fixture (handler -> service -> repository,
+// with handler also importing repository directly) to exercise package
+// loading and the isMainModule filter together, not just SCCs/SkipLayerEdges
+// against hand-built graphs.
+func TestBuildHandlerServiceRepositoryFixture(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	fixture, err := filepath.Abs(filepath.Join("testdata", "fixture"))
+	if err != nil {
+		t.Fatalf("Abs: %v", err)
+	}
+	if err := os.Chdir(fixture); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	layers := &config.Layers{
+		Layers: []config.Layer{
+			{Name: "handler", Package: "*/handler"},
+			{Name: "service", Package: "*/service"},
+			{Name: "repository", Package: "*/repository"},
+		},
+		Allow: map[string][]string{
+			"handler": {"service"},
+			"service": {"repository"},
+		},
+	}
+
+	g, err := Build([]string{"./..."}, layers)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	const (
+		handler    = "github.com/example/grapharch/handler"
+		service    = "github.com/example/grapharch/service"
+		repository = "github.com/example/grapharch/repository"
+	)
+	if len(g.Nodes) != 3 {
+		t.Fatalf("len(Nodes) = %d, want 3 (stdlib nodes must be filtered out): %v", len(g.Nodes), g.Nodes)
+	}
+	for path, wantLayer := range map[string]string{handler: "handler", service: "service", repository: "repository"} {
+		node, ok := g.Nodes[path]
+		if !ok {
+			t.Fatalf("Nodes missing %s", path)
+		}
+		if node.Layer != wantLayer {
+			t.Errorf("Nodes[%s].Layer = %q, want %q", path, node.Layer, wantLayer)
+		}
+	}
+
+	sortedEdges := append([]string{}, g.Edges[handler]...)
+	sort.Strings(sortedEdges)
+	wantEdges := []string{repository, service}
+	if !reflect.DeepEqual(sortedEdges, wantEdges) {
+		t.Errorf("Edges[%s] = %v, want %v (net/http must be filtered out)", handler, sortedEdges, wantEdges)
+	}
+
+	skips := g.SkipLayerEdges(layers)
+	if len(skips) != 1 {
+		t.Fatalf("SkipLayerEdges() = %d edges, want 1: %v", len(skips), skips)
+	}
+	if skips[0].From != handler || skips[0].To != repository {
+		t.Errorf("SkipLayerEdges()[0] = %+v, want From %s To %s", skips[0], handler, repository)
+	}
+}
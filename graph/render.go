@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToDot renders the graph as Graphviz dot, coloring nodes by layer so CI
+// can attach the rendered image to a PR.
+func (g *Graph) ToDot() string {
+	var b strings.Builder
+	b.WriteString("digraph thymus {\n")
+	for _, path := range g.sortedPaths() {
+		node := g.Nodes[path]
+		b.WriteString(fmt.Sprintf("  %q [label=%q, style=filled, fillcolor=%q];\n",
+			path, path, layerColor(node.Layer)))
+	}
+	for _, from := range g.sortedPaths() {
+		for _, to := range g.Edges[from] {
+			b.WriteString(fmt.Sprintf("  %q -> %q;\n", from, to))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) ToMermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	ids := map[string]string{}
+	for i, path := range g.sortedPaths() {
+		id := fmt.Sprintf("n%d", i)
+		ids[path] = id
+		b.WriteString(fmt.Sprintf("  %s[%q]\n", id, path))
+	}
+	for _, from := range g.sortedPaths() {
+		for _, to := range g.Edges[from] {
+			b.WriteString(fmt.Sprintf("  %s --> %s\n", ids[from], ids[to]))
+		}
+	}
+	return b.String()
+}
+
+func (g *Graph) sortedPaths() []string {
+	paths := make([]string, 0, len(g.Nodes))
+	for path := range g.Nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// layerColor picks a stable, readable-on-white fill color per layer name so
+// the same layer always renders the same color across runs.
+func layerColor(layer string) string {
+	if layer == "" {
+		return "#e0e0e0"
+	}
+	palette := []string{"#a6cee3", "#b2df8a", "#fb9a99", "#fdbf6f", "#cab2d6"}
+	sum := 0
+	for _, r := range layer {
+		sum += int(r)
+	}
+	return palette[sum%len(palette)]
+}
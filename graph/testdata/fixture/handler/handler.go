@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/example/grapharch/repository"
+	"github.com/example/grapharch/service"
+)
+
+// Handler skips the service layer and reaches into repository directly,
+// which SkipLayerEdges should flag.
+type Handler struct {
+	svc  *service.UserService
+	repo *repository.UserRepo
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = h.svc.FindAll()
+	_ = h.repo.FindAll()
+}
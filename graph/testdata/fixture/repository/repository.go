@@ -0,0 +1,7 @@
+package repository
+
+type UserRepo struct{}
+
+func (r *UserRepo) FindAll() []string {
+	return nil
+}
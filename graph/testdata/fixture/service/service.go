@@ -0,0 +1,11 @@
+package service
+
+import "github.com/example/grapharch/repository"
+
+type UserService struct {
+	repo *repository.UserRepo
+}
+
+func (s *UserService) FindAll() []string {
+	return s.repo.FindAll()
+}
@@ -0,0 +1,97 @@
+// Package handlerdetect recognizes HTTP handler functions and methods
+// across frameworks. thymus's layering check uses it to tell whether a
+// function is a "handler" even when it doesn't implement the standard
+// library's http.Handler interface, e.g. a gin.HandlerFunc or an
+// echo.HandlerFunc.
+package handlerdetect
+
+import "go/types"
+
+// Matcher reports whether sig is the signature of a handler function for a
+// particular framework.
+type Matcher func(sig *types.Signature) bool
+
+var registry = map[string]Matcher{}
+var order []string
+
+// RegisterHandlerKind adds a named matcher to the registry. Callers can use
+// it to teach thymus about an in-house or less common framework; the
+// built-in kinds ("net/http", "gin", "echo") are registered by this
+// package's init. chi handlers match the "net/http" kind.
+func RegisterHandlerKind(name string, matcher Matcher) {
+	if _, exists := registry[name]; !exists {
+		order = append(order, name)
+	}
+	registry[name] = matcher
+}
+
+func init() {
+	RegisterHandlerKind("net/http", isNetHTTPHandler)
+	RegisterHandlerKind("gin", isGinHandlerFunc)
+	RegisterHandlerKind("echo", isEchoHandlerFunc)
+	// chi and Go 1.22's method-prefixed ServeMux both register plain
+	// http.HandlerFunc values, so they're covered by the net/http matcher;
+	// what changes between them is the routing pattern, not the handler's
+	// signature.
+}
+
+// Kind reports which registered handler kind sig matches, if any. Matchers
+// are tried in registration order and the first match wins.
+func Kind(sig *types.Signature) (string, bool) {
+	for _, name := range order {
+		if registry[name](sig) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// IsHandler reports whether sig matches any registered handler kind.
+func IsHandler(sig *types.Signature) bool {
+	_, ok := Kind(sig)
+	return ok
+}
+
+func isNetHTTPHandler(sig *types.Signature) bool {
+	// func(http.ResponseWriter, *http.Request)
+	if sig.Params().Len() != 2 || sig.Results().Len() != 0 {
+		return false
+	}
+	return isNamed(sig.Params().At(0).Type(), "net/http", "ResponseWriter") &&
+		isPointerToNamed(sig.Params().At(1).Type(), "net/http", "Request")
+}
+
+func isGinHandlerFunc(sig *types.Signature) bool {
+	// func(*gin.Context)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 0 {
+		return false
+	}
+	return isPointerToNamed(sig.Params().At(0).Type(), "github.com/gin-gonic/gin", "Context")
+}
+
+func isEchoHandlerFunc(sig *types.Signature) bool {
+	// func(echo.Context) error
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	if !isNamed(sig.Params().At(0).Type(), "github.com/labstack/echo/v4", "Context") {
+		return false
+	}
+	return types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj() == nil || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+func isPointerToNamed(t types.Type, pkgPath, name string) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	return isNamed(ptr.Elem(), pkgPath, name)
+}
@@ -0,0 +1,135 @@
+package handlerdetect
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// stubImporter resolves a fixed set of in-memory framework stubs (gin,
+// echo) and falls back to the real stdlib importer for everything else,
+// so signatures can be type-checked against gin.Context/echo.Context
+// without vendoring either framework.
+type stubImporter map[string]*types.Package
+
+func (s stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := s[path]; ok {
+		return pkg, nil
+	}
+	return importer.Default().Import(path)
+}
+
+func typeCheck(t *testing.T, path, src string, imp types.Importer) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path+".go", src, 0)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", path, err)
+	}
+	pkg, err := (&types.Config{Importer: imp}).Check(path, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("type-checking %s: %v", path, err)
+	}
+	return pkg
+}
+
+func signatureOf(t *testing.T, pkg *types.Package, funcName string) *types.Signature {
+	t.Helper()
+	obj := pkg.Scope().Lookup(funcName)
+	if obj == nil {
+		t.Fatalf("no func %s in package %s", funcName, pkg.Path())
+	}
+	sig, ok := obj.Type().(*types.Signature)
+	if !ok {
+		t.Fatalf("%s is not a func", funcName)
+	}
+	return sig
+}
+
+func testImporter(t *testing.T) stubImporter {
+	t.Helper()
+	return stubImporter{
+		"github.com/gin-gonic/gin": typeCheck(t, "github.com/gin-gonic/gin",
+			"package gin\n\ntype Context struct{}\n", nil),
+		"github.com/labstack/echo/v4": typeCheck(t, "github.com/labstack/echo/v4",
+			"package echo\n\ntype Context interface{}\n", nil),
+	}
+}
+
+func TestIsNetHTTPHandler(t *testing.T) {
+	pkg := typeCheck(t, "nethttpexample", `package nethttpexample
+
+import "net/http"
+
+func Handler(w http.ResponseWriter, r *http.Request) {}
+
+func NotHandler(w http.ResponseWriter) {}
+`, importer.Default())
+
+	if !isNetHTTPHandler(signatureOf(t, pkg, "Handler")) {
+		t.Error("isNetHTTPHandler(Handler) = false, want true")
+	}
+	if isNetHTTPHandler(signatureOf(t, pkg, "NotHandler")) {
+		t.Error("isNetHTTPHandler(NotHandler) = true, want false")
+	}
+}
+
+func TestIsGinHandlerFunc(t *testing.T) {
+	pkg := typeCheck(t, "ginexample", `package ginexample
+
+import "github.com/gin-gonic/gin"
+
+func Handler(c *gin.Context) {}
+
+func NotHandler(c gin.Context) {}
+`, testImporter(t))
+
+	if !isGinHandlerFunc(signatureOf(t, pkg, "Handler")) {
+		t.Error("isGinHandlerFunc(Handler) = false, want true")
+	}
+	if isGinHandlerFunc(signatureOf(t, pkg, "NotHandler")) {
+		t.Error("isGinHandlerFunc(NotHandler) = true, want false (gin.Context, not *gin.Context)")
+	}
+}
+
+func TestIsEchoHandlerFunc(t *testing.T) {
+	pkg := typeCheck(t, "echoexample", `package echoexample
+
+import "github.com/labstack/echo/v4"
+
+func Handler(c echo.Context) error { return nil }
+
+func NotHandler(c echo.Context) {}
+`, testImporter(t))
+
+	if !isEchoHandlerFunc(signatureOf(t, pkg, "Handler")) {
+		t.Error("isEchoHandlerFunc(Handler) = false, want true")
+	}
+	if isEchoHandlerFunc(signatureOf(t, pkg, "NotHandler")) {
+		t.Error("isEchoHandlerFunc(NotHandler) = true, want false (no error result)")
+	}
+}
+
+func TestKindTriesRegisteredMatchersInOrder(t *testing.T) {
+	pkg := typeCheck(t, "mixedexample", `package mixedexample
+
+import "github.com/gin-gonic/gin"
+
+func Handler(c *gin.Context) {}
+
+func Plain() {}
+`, testImporter(t))
+
+	if kind, ok := Kind(signatureOf(t, pkg, "Handler")); !ok || kind != "gin" {
+		t.Errorf(`Kind(Handler) = %q, %v, want "gin", true`, kind, ok)
+	}
+	if _, ok := Kind(signatureOf(t, pkg, "Plain")); ok {
+		t.Error("Kind(Plain) = _, true, want false")
+	}
+	if IsHandler(signatureOf(t, pkg, "Plain")) {
+		t.Error("IsHandler(Plain) = true, want false")
+	}
+}